@@ -0,0 +1,88 @@
+package unitypackage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compression identifies the compression format wrapping a tar stream.
+type Compression int
+
+const (
+	// CompressionNone indicates an uncompressed (raw) tar stream.
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionBzip2
+	CompressionXz
+	CompressionZstd
+)
+
+// magic bytes used to sniff the compression format of a stream, in the same
+// spirit as Docker's archive.DetectCompression.
+var (
+	gzipMagic  = []byte{0x1F, 0x8B}
+	bzip2Magic = []byte{0x42, 0x5A, 0x68}
+	xzMagic    = []byte{0xFD, 0x37, 0x7A, 0x58, 0x5A, 0x00}
+	zstdMagic  = []byte{0x28, 0xB5, 0x2F, 0xFD}
+)
+
+// detectCompression inspects the first few bytes of peek and returns the
+// compression format they indicate, or CompressionNone if nothing matches.
+func detectCompression(peek []byte) Compression {
+	switch {
+	case bytes.HasPrefix(peek, xzMagic):
+		return CompressionXz
+	case bytes.HasPrefix(peek, zstdMagic):
+		return CompressionZstd
+	case bytes.HasPrefix(peek, bzip2Magic):
+		return CompressionBzip2
+	case bytes.HasPrefix(peek, gzipMagic):
+		return CompressionGzip
+	default:
+		return CompressionNone
+	}
+}
+
+// DecompressStream wraps r in the appropriate decompressing io.Reader based
+// on its leading magic bytes, falling back to returning r unchanged (wrapped
+// so it satisfies io.ReadCloser) when no known compression is detected.
+func DecompressStream(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+
+	peek, err := br.Peek(6)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to peek archive header: %w", err)
+	}
+
+	switch detectCompression(peek) {
+	case CompressionGzip:
+		gzipReader, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gzipReader, nil
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(br)), nil
+	case CompressionXz:
+		xzReader, err := xz.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create xz reader: %w", err)
+		}
+		return io.NopCloser(xzReader), nil
+	case CompressionZstd:
+		zstdReader, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return zstdReader.IOReadCloser(), nil
+	default:
+		return io.NopCloser(br), nil
+	}
+}
@@ -0,0 +1,116 @@
+package unitypackage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// helloBzip2 is "hello bzip2" compressed with bzip2, used to exercise
+// DecompressStream's bzip2 path since compress/bzip2 in the standard library
+// only implements a reader, not a writer.
+var helloBzip2 = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x55, 0x5a,
+	0x44, 0xf7, 0x00, 0x00, 0x02, 0x19, 0x80, 0x40, 0x00, 0x10, 0x00, 0x12,
+	0x64, 0xc0, 0x10, 0x20, 0x00, 0x22, 0x00, 0x69, 0xea, 0x10, 0x03, 0x05,
+	0xd3, 0xb6, 0x21, 0x83, 0xc5, 0xdc, 0x91, 0x4e, 0x14, 0x24, 0x15, 0x56,
+	0x91, 0x3d, 0xc0,
+}
+
+func TestDetectCompression(t *testing.T) {
+	tests := []struct {
+		name string
+		peek []byte
+		want Compression
+	}{
+		{"gzip", []byte{0x1F, 0x8B, 0x08, 0x00, 0x00, 0x00}, CompressionGzip},
+		{"bzip2", []byte{0x42, 0x5A, 0x68, 0x39, 0x31, 0x41}, CompressionBzip2},
+		{"xz", []byte{0xFD, 0x37, 0x7A, 0x58, 0x5A, 0x00}, CompressionXz},
+		{"zstd", []byte{0x28, 0xB5, 0x2F, 0xFD, 0x00, 0x00}, CompressionZstd},
+		{"uncompressed tar", []byte("ustar\x00"), CompressionNone},
+		{"empty", nil, CompressionNone},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := detectCompression(tc.peek); got != tc.want {
+				t.Errorf("detectCompression(%x) = %v, want %v", tc.peek, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecompressStreamRoundTrip(t *testing.T) {
+	const want = "hello unitypackage"
+
+	gzipBuf := &bytes.Buffer{}
+	gw := gzip.NewWriter(gzipBuf)
+	if _, err := gw.Write([]byte(want)); err != nil {
+		t.Fatalf("failed to write gzip stream: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+
+	xzBuf := &bytes.Buffer{}
+	xw, err := xz.NewWriter(xzBuf)
+	if err != nil {
+		t.Fatalf("failed to create xz writer: %v", err)
+	}
+	if _, err := xw.Write([]byte(want)); err != nil {
+		t.Fatalf("failed to write xz stream: %v", err)
+	}
+	if err := xw.Close(); err != nil {
+		t.Fatalf("failed to close xz writer: %v", err)
+	}
+
+	zstdBuf := &bytes.Buffer{}
+	zw, err := zstd.NewWriter(zstdBuf)
+	if err != nil {
+		t.Fatalf("failed to create zstd writer: %v", err)
+	}
+	if _, err := zw.Write([]byte(want)); err != nil {
+		t.Fatalf("failed to write zstd stream: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zstd writer: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stream []byte
+	}{
+		{"gzip", gzipBuf.Bytes()},
+		{"bzip2", helloBzip2},
+		{"xz", xzBuf.Bytes()},
+		{"zstd", zstdBuf.Bytes()},
+		{"uncompressed", []byte(want)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := DecompressStream(bytes.NewReader(tc.stream))
+			if err != nil {
+				t.Fatalf("DecompressStream failed: %v", err)
+			}
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("failed to read decompressed stream: %v", err)
+			}
+
+			wantContent := want
+			if tc.name == "bzip2" {
+				wantContent = "hello bzip2"
+			}
+			if string(got) != wantContent {
+				t.Errorf("decompressed content = %q, want %q", got, wantContent)
+			}
+		})
+	}
+}
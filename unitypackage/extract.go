@@ -0,0 +1,246 @@
+package unitypackage
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+)
+
+// pendingEntry tracks what has been seen so far for a single GUID while
+// ExtractTo is still streaming through the archive. Only the small pathname
+// file is buffered in memory; large blobs (asset, asset.meta, preview.png)
+// are staged to temp files and handed to the writer pool as soon as the
+// pathname for their GUID is known.
+type pendingEntry struct {
+	pathname string
+	sawAsset bool // true once an "asset" member has been seen, i.e. this is a file, not a directory
+
+	stagedAsset   string
+	stagedMeta    string
+	stagedPreview string
+}
+
+// ExtractTo writes the archive's assets into targetDir, laid out as a Unity
+// project: each asset at its recorded pathname (relative to targetDir),
+// alongside its "<pathname>.meta" sidecar and, if opts.Previews is set, a
+// "<pathname>.preview.png". Entries with no "asset" member are directories;
+// they are created (with their own .meta) rather than written as files.
+//
+// The archive is decoded in a single, necessarily sequential pass, but the
+// actual disk writes are handed off to a pool of opts.Workers goroutines
+// (default runtime.NumCPU()) so that large numbers of small assets don't
+// serialize behind one writer. opts.OnProgress, if set, is called as bytes
+// are read and as writes complete.
+//
+// Calling ExtractTo after Assets (or a second time) on the same Unpacker
+// returns ErrStreamConsumed, since both methods make a single forward pass
+// over the same archive stream.
+func (u *Unpacker) ExtractTo(targetDir string, opts ExtractOptions) (err error) {
+	if u.consumed {
+		return ErrStreamConsumed
+	}
+	u.consumed = true
+
+	pool := newWriterPool(opts.Workers, opts.OnProgress)
+	pending := make(map[string]*pendingEntry)
+
+	defer func() {
+		if poolErr := pool.closeAndWait(); err == nil {
+			err = poolErr
+		}
+		// On any error, entries that were staged but never reached a
+		// pathname (so never got submitted to the pool) would otherwise
+		// leak their ".unitypackage-staged-*" temp files into targetDir.
+		if err != nil {
+			cleanupStaged(pending)
+		}
+	}()
+
+	tarReader := tar.NewReader(u.source)
+	for {
+		header, terr := tarReader.Next()
+		if terr == io.EOF {
+			break
+		}
+		if terr != nil {
+			return fmt.Errorf("failed to read tar archive: %w", terr)
+		}
+		if header.Typeflag == tar.TypeSymlink || header.Typeflag == tar.TypeLink {
+			// See ErrUnsupportedEntryType: links are rejected outright rather
+			// than target-validated, since ExtractTo's staged-then-renamed
+			// writes mean a link's target may not exist at destDir yet.
+			return fmt.Errorf("%w: %s", ErrUnsupportedEntryType, header.Name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		guid, member := splitGUIDMember(header.Name)
+		if guid == "" {
+			continue
+		}
+
+		entry, ok := pending[guid]
+		if !ok {
+			entry = &pendingEntry{}
+			pending[guid] = entry
+		}
+
+		switch member {
+		case "pathname":
+			content, rerr := io.ReadAll(tarReader)
+			if rerr != nil {
+				return fmt.Errorf("failed to read %s: %w", header.Name, rerr)
+			}
+			pool.addBytesRead(int64(len(content)))
+			entry.pathname = string(content)
+			if ferr := flushPending(targetDir, pool, entry); ferr != nil {
+				return ferr
+			}
+
+		case "asset":
+			entry.sawAsset = true
+			if berr := writeOrStageBlob(targetDir, pool, entry, "", &entry.stagedAsset, tarReader); berr != nil {
+				return berr
+			}
+
+		case "asset.meta":
+			if berr := writeOrStageBlob(targetDir, pool, entry, ".meta", &entry.stagedMeta, tarReader); berr != nil {
+				return berr
+			}
+
+		case "preview.png":
+			if !opts.Previews {
+				continue
+			}
+			if berr := writeOrStageBlob(targetDir, pool, entry, ".preview.png", &entry.stagedPreview, tarReader); berr != nil {
+				return berr
+			}
+		}
+	}
+
+	for guid, entry := range pending {
+		anyStaged := entry.stagedAsset != "" || entry.stagedMeta != "" || entry.stagedPreview != ""
+
+		if entry.pathname == "" {
+			if anyStaged {
+				// cleanupStaged in the deferred error handling above removes
+				// the staged files; just report the malformed archive here.
+				return fmt.Errorf("unitypackage: asset %s has no accompanying pathname", guid)
+			}
+			continue
+		}
+
+		if entry.sawAsset {
+			continue
+		}
+
+		// No "asset" member means this entry is a directory; its pathname
+		// and (optional) .meta were already handed off above, so just make
+		// sure the directory itself exists.
+		targetPath, serr := safeJoin(targetDir, entry.pathname)
+		if serr != nil {
+			return serr
+		}
+		if merr := os.MkdirAll(targetPath, os.FileMode(0755)); merr != nil {
+			return fmt.Errorf("failed to create directory %s: %w", targetPath, merr)
+		}
+	}
+
+	return nil
+}
+
+// writeOrStageBlob stages r to a temp file and either submits it to pool
+// immediately (if entry.pathname is already known) or records it in *staged
+// for flushPending to submit once the pathname arrives.
+func writeOrStageBlob(targetDir string, pool *writerPool, entry *pendingEntry, suffix string, staged *string, r io.Reader) error {
+	stagedPath, n, err := stageToTempFile(targetDir, r)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", suffix, err)
+	}
+	pool.addBytesRead(n)
+
+	if entry.pathname == "" {
+		*staged = stagedPath
+		return nil
+	}
+
+	targetPath, err := safeJoin(targetDir, entry.pathname)
+	if err != nil {
+		os.Remove(stagedPath)
+		return err
+	}
+	pool.submit(targetPath+suffix, stagedPath)
+
+	return nil
+}
+
+// flushPending submits any blobs staged for entry to pool now that
+// entry.pathname is known.
+func flushPending(targetDir string, pool *writerPool, entry *pendingEntry) error {
+	targetPath, err := safeJoin(targetDir, entry.pathname)
+	if err != nil {
+		// cleanupStaged in ExtractTo's deferred error handling removes these.
+		return err
+	}
+
+	moves := []struct {
+		suffix string
+		staged *string
+	}{
+		{"", &entry.stagedAsset},
+		{".meta", &entry.stagedMeta},
+		{".preview.png", &entry.stagedPreview},
+	}
+
+	for _, mv := range moves {
+		if *mv.staged == "" {
+			continue
+		}
+		pool.submit(targetPath+mv.suffix, *mv.staged)
+		*mv.staged = ""
+	}
+
+	return nil
+}
+
+// cleanupStaged removes every staged temp file still referenced by pending.
+// It is called when ExtractTo is about to return an error, since any entry
+// left staged at that point will never be flushed otherwise.
+func cleanupStaged(pending map[string]*pendingEntry) {
+	for _, entry := range pending {
+		if entry.stagedAsset != "" {
+			os.Remove(entry.stagedAsset)
+		}
+		if entry.stagedMeta != "" {
+			os.Remove(entry.stagedMeta)
+		}
+		if entry.stagedPreview != "" {
+			os.Remove(entry.stagedPreview)
+		}
+	}
+}
+
+// stageToTempFile streams r into a hidden temporary file inside dir (so a
+// later move into place is a same-filesystem os.Rename), returning its path
+// and the number of bytes copied.
+func stageToTempFile(dir string, r io.Reader) (path string, n int64, err error) {
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return "", 0, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	tempFile, err := os.CreateTemp(dir, ".unitypackage-staged-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create staging file: %w", err)
+	}
+	defer tempFile.Close()
+
+	written, err := io.Copy(tempFile, r)
+	if err != nil {
+		os.Remove(tempFile.Name())
+		return "", 0, fmt.Errorf("failed to write staging file: %w", err)
+	}
+
+	return tempFile.Name(), written, nil
+}
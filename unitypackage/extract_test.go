@@ -0,0 +1,74 @@
+package unitypackage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractToWithPreviewsWritesSidecar(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	playerPath := filepath.Join(srcDir, "Assets", "Scripts", "Player.cs")
+	previewContent := []byte("fake png bytes")
+	if err := os.WriteFile(playerPath+".preview.png", previewContent, 0644); err != nil {
+		t.Fatalf("failed to write preview: %v", err)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	if err := u.ExtractTo(destDir, ExtractOptions{Previews: true}); err != nil {
+		t.Fatalf("ExtractTo failed: %v", err)
+	}
+
+	wantPreview := filepath.Join(destDir, "Assets", "Scripts", "Player.cs.preview.png")
+	got, err := os.ReadFile(wantPreview)
+	if err != nil {
+		t.Fatalf("expected preview at %s: %v", wantPreview, err)
+	}
+	if string(got) != string(previewContent) {
+		t.Errorf("preview content = %q, want %q", got, previewContent)
+	}
+}
+
+func TestExtractToWithoutPreviewsSkipsSidecar(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	playerPath := filepath.Join(srcDir, "Assets", "Scripts", "Player.cs")
+	if err := os.WriteFile(playerPath+".preview.png", []byte("fake png bytes"), 0644); err != nil {
+		t.Fatalf("failed to write preview: %v", err)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	if err := u.ExtractTo(destDir, ExtractOptions{}); err != nil {
+		t.Fatalf("ExtractTo failed: %v", err)
+	}
+
+	wantPreview := filepath.Join(destDir, "Assets", "Scripts", "Player.cs.preview.png")
+	if _, err := os.Stat(wantPreview); !os.IsNotExist(err) {
+		t.Errorf("expected no preview at %s when Previews is false, stat err = %v", wantPreview, err)
+	}
+}
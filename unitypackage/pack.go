@@ -0,0 +1,162 @@
+package unitypackage
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackDirectory walks srcAssetsDir and writes a gzip-compressed
+// .unitypackage tar to outFile, the inverse of Unpacker.ExtractTo. Every
+// "*.meta" file found is treated as describing the asset (or directory)
+// next to it: its GUID is read from the meta file and used to emit a
+// "<guid>/pathname", "<guid>/asset.meta", and, for files, "<guid>/asset" and
+// (if present) "<guid>/preview.png".
+func PackDirectory(srcAssetsDir, outFile string) (err error) {
+	out, err := os.Create(outFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file %s: %w", outFile, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(out)
+	defer func() {
+		// gzip.Writer.Close flushes the remaining buffer and footer
+		// checksum; unlike a plain file Close, a failure here means the
+		// archive is truncated, so it must not be swallowed.
+		if closeErr := gzipWriter.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer func() {
+		// tar.Writer.Close writes the final zero-block trailer; same
+		// reasoning as gzipWriter above.
+		if closeErr := tarWriter.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	walkErr := filepath.WalkDir(srcAssetsDir, func(metaPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(metaPath, ".meta") {
+			return nil
+		}
+
+		assetPath := strings.TrimSuffix(metaPath, ".meta")
+		assetInfo, statErr := os.Stat(assetPath)
+		if statErr != nil {
+			// An orphan .meta file with no corresponding asset; skip it.
+			return nil
+		}
+
+		guid, guidErr := readGUID(metaPath)
+		if guidErr != nil {
+			return fmt.Errorf("failed to read GUID from %s: %w", metaPath, guidErr)
+		}
+
+		relPath, relErr := filepath.Rel(srcAssetsDir, assetPath)
+		if relErr != nil {
+			return fmt.Errorf("failed to compute relative path for %s: %w", assetPath, relErr)
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if err := writeTarBytes(tarWriter, guid+"/pathname", []byte(relPath)); err != nil {
+			return err
+		}
+		if err := writeTarFile(tarWriter, guid+"/asset.meta", metaPath); err != nil {
+			return err
+		}
+
+		if assetInfo.IsDir() {
+			return nil
+		}
+		if err := writeTarFile(tarWriter, guid+"/asset", assetPath); err != nil {
+			return err
+		}
+
+		previewPath := assetPath + ".preview.png"
+		if _, err := os.Stat(previewPath); err == nil {
+			if err := writeTarFile(tarWriter, guid+"/preview.png", previewPath); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if walkErr != nil {
+		return fmt.Errorf("failed to walk %s: %w", srcAssetsDir, walkErr)
+	}
+
+	return nil
+}
+
+// readGUID extracts the "guid:" field from a Unity .meta YAML file.
+func readGUID(metaPath string) (string, error) {
+	f, err := os.Open(metaPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if guid, ok := strings.CutPrefix(line, "guid:"); ok {
+			return strings.TrimSpace(guid), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no guid field found in %s", metaPath)
+}
+
+// writeTarFile streams the file at srcPath into tw as name.
+func writeTarFile(tw *tar.Writer, name, srcPath string) error {
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", srcPath, err)
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: info.Size()}); err != nil {
+		return fmt.Errorf("failed to write tar header for %s: %w", name, err)
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	if _, err := io.Copy(tw, src); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeTarBytes writes content into tw as name.
+func writeTarBytes(tw *tar.Writer, name string, content []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+		return fmt.Errorf("failed to write tar header for %s: %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
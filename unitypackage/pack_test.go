@@ -0,0 +1,95 @@
+package unitypackage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMeta(t *testing.T, path, guid string) {
+	t.Helper()
+	content := "fileFormatVersion: 2\nguid: " + guid + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// buildProject lays out a small Unity project tree:
+//
+//	Assets.meta                          (directory)
+//	Assets/Scripts.meta                  (directory)
+//	Assets/Scripts/Player.cs(.meta)      (file)
+func buildProject(t *testing.T, root string) {
+	t.Helper()
+
+	scriptsDir := filepath.Join(root, "Assets", "Scripts")
+	if err := os.MkdirAll(scriptsDir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", scriptsDir, err)
+	}
+
+	writeMeta(t, filepath.Join(root, "Assets.meta"), "11111111111111111111111111111111")
+	writeMeta(t, filepath.Join(root, "Assets", "Scripts.meta"), "22222222222222222222222222222222")
+
+	playerPath := filepath.Join(scriptsDir, "Player.cs")
+	if err := os.WriteFile(playerPath, []byte("public class Player {}\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", playerPath, err)
+	}
+	writeMeta(t, playerPath+".meta", "33333333333333333333333333333333")
+}
+
+func TestPackAndExtractRoundTrip(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	if err := u.ExtractTo(destDir, ExtractOptions{}); err != nil {
+		t.Fatalf("ExtractTo failed: %v", err)
+	}
+
+	wantFile := filepath.Join(destDir, "Assets", "Scripts", "Player.cs")
+	got, err := os.ReadFile(wantFile)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", wantFile, err)
+	}
+	if string(got) != "public class Player {}\n" {
+		t.Errorf("Player.cs content = %q, want %q", got, "public class Player {}\n")
+	}
+
+	wantMeta := filepath.Join(destDir, "Assets", "Scripts", "Player.cs.meta")
+	if _, err := os.Stat(wantMeta); err != nil {
+		t.Errorf("expected %s to exist: %v", wantMeta, err)
+	}
+
+	for _, dir := range []string{
+		filepath.Join(destDir, "Assets"),
+		filepath.Join(destDir, "Assets", "Scripts"),
+	} {
+		info, err := os.Stat(dir)
+		if err != nil {
+			t.Fatalf("expected directory %s to exist: %v", dir, err)
+		}
+		if !info.IsDir() {
+			t.Errorf("expected %s to be a directory", dir)
+		}
+	}
+
+	for _, meta := range []string{
+		filepath.Join(destDir, "Assets.meta"),
+		filepath.Join(destDir, "Assets", "Scripts.meta"),
+	} {
+		if _, err := os.Stat(meta); err != nil {
+			t.Errorf("expected %s to exist: %v", meta, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package unitypackage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsafePath is returned when an archive entry's path is absolute or would
+// resolve outside of the destination directory (a so-called "zip-slip").
+// Symlink and hardlink targets are not checked against it; link entries are
+// rejected with ErrUnsupportedEntryType instead.
+var ErrUnsafePath = errors.New("unitypackage: unsafe path in archive entry")
+
+// ErrUnsupportedEntryType is returned when an archive entry is a symlink or
+// hardlink.
+//
+// This is a deliberate design decision, not an oversight: a validated-symlink
+// scheme (resolve the link target, verify it also stays within destDir, then
+// os.Symlink/os.Link it into place) is sound for a single-pass extractor that
+// writes entries in archive order, but ExtractTo stages asset/asset.meta
+// blobs to temp files and only renames them into place once each GUID's
+// pathname is known, so a link's target may not exist at destDir yet when
+// the link entry itself is decoded — validating against a partially-written
+// tree can't distinguish "target is legitimately pending" from "target is
+// outside destDir". A .unitypackage has no legitimate use for symlinks or
+// hardlinks in the first place (every real-world archive is plain
+// asset/asset.meta/pathname/preview.png members), so Assets and ExtractTo
+// reject link entries outright rather than attempt to validate them.
+var ErrUnsupportedEntryType = errors.New("unitypackage: symlink/hardlink archive entries are not supported")
+
+// safeJoin resolves name against destDir and verifies that the result stays
+// within destDir, returning ErrUnsafePath if it does not.
+func safeJoin(destDir, name string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(cleaned) || strings.HasPrefix(cleaned, string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is an absolute path", ErrUnsafePath, name)
+	}
+	targetPath := filepath.Join(destDir, cleaned)
+
+	rel, err := filepath.Rel(destDir, targetPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrUnsafePath, name, err)
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s escapes destination directory", ErrUnsafePath, name)
+	}
+
+	return targetPath, nil
+}
@@ -0,0 +1,126 @@
+package unitypackage
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSafeJoinRejectsEscape(t *testing.T) {
+	destDir := filepath.Join(t.TempDir(), "out")
+
+	names := []string{
+		"../../etc/passwd",
+		"../../../etc/passwd",
+		"/etc/passwd",
+		"foo/../../bar",
+	}
+
+	for _, name := range names {
+		targetPath, err := safeJoin(destDir, name)
+		if err != nil {
+			// Rejecting outright is also an acceptable outcome.
+			if !strings.Contains(err.Error(), "unsafe path") {
+				t.Errorf("safeJoin(%q) returned unexpected error: %v", name, err)
+			}
+			continue
+		}
+
+		rel, relErr := filepath.Rel(destDir, targetPath)
+		if relErr != nil {
+			t.Fatalf("safeJoin(%q) = %q, not relative to destDir: %v", name, targetPath, relErr)
+		}
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			t.Errorf("safeJoin(%q) = %q escapes destDir %q", name, targetPath, destDir)
+		}
+	}
+}
+
+func TestSafeJoinAllowsOrdinaryPaths(t *testing.T) {
+	destDir := filepath.Join(t.TempDir(), "out")
+
+	targetPath, err := safeJoin(destDir, "Assets/Scripts/Player.cs")
+	if err != nil {
+		t.Fatalf("safeJoin returned unexpected error: %v", err)
+	}
+
+	want := filepath.Join(destDir, "Assets", "Scripts", "Player.cs")
+	if targetPath != want {
+		t.Errorf("safeJoin = %q, want %q", targetPath, want)
+	}
+}
+
+// archiveWithLink builds a minimal tar archive containing a single link
+// entry of the given type, pointing at linkname.
+func archiveWithLink(t *testing.T, typeflag byte, name, linkname string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	header := &tar.Header{
+		Name:     name,
+		Typeflag: typeflag,
+		Linkname: linkname,
+		Mode:     0644,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestAssetsRejectsSymlinkAndHardlinkEntries(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		typeflag byte
+	}{
+		{"symlink", tar.TypeSymlink},
+		{"hardlink", tar.TypeLink},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			archive := archiveWithLink(t, tc.typeflag, "11111111111111111111111111111111/asset", "/etc/passwd")
+
+			u, err := NewFromReader(archive)
+			if err != nil {
+				t.Fatalf("NewFromReader failed: %v", err)
+			}
+			defer u.Close()
+
+			if _, err := u.Assets(); !errors.Is(err, ErrUnsupportedEntryType) {
+				t.Fatalf("Assets() error = %v, want ErrUnsupportedEntryType", err)
+			}
+		})
+	}
+}
+
+func TestExtractToRejectsSymlinkAndHardlinkEntries(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		typeflag byte
+	}{
+		{"symlink", tar.TypeSymlink},
+		{"hardlink", tar.TypeLink},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			archive := archiveWithLink(t, tc.typeflag, "11111111111111111111111111111111/asset", "/etc/passwd")
+
+			u, err := NewFromReader(archive)
+			if err != nil {
+				t.Fatalf("NewFromReader failed: %v", err)
+			}
+			defer u.Close()
+
+			destDir := filepath.Join(t.TempDir(), "out")
+			if err := u.ExtractTo(destDir, ExtractOptions{}); !errors.Is(err, ErrUnsupportedEntryType) {
+				t.Fatalf("ExtractTo() error = %v, want ErrUnsupportedEntryType", err)
+			}
+		})
+	}
+}
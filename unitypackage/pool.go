@@ -0,0 +1,119 @@
+package unitypackage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// writeJob is a disk write task handed from the (necessarily single-
+// threaded) tar-reading loop to the writer pool: a blob already staged to a
+// temp file, ready to be moved into place.
+type writeJob struct {
+	destPath string
+	tempFile string
+}
+
+// writerPool moves staged blobs into their final location using a bounded
+// number of concurrent goroutines. Tar decoding itself stays sequential, but
+// for archives with many small assets the disk writes it produces don't
+// need to be.
+type writerPool struct {
+	jobs chan writeJob
+	wg   sync.WaitGroup
+
+	mu       sync.Mutex
+	firstErr error
+
+	bytesRead   int64
+	entriesDone int64
+	onProgress  ProgressFunc
+}
+
+// newWriterPool starts workers concurrent writer goroutines (runtime.NumCPU()
+// if workers <= 0) and returns a pool ready to accept jobs via submit.
+func newWriterPool(workers int, onProgress ProgressFunc) *writerPool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	p := &writerPool{
+		jobs:       make(chan writeJob, workers*4),
+		onProgress: onProgress,
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.run()
+	}
+
+	return p
+}
+
+func (p *writerPool) run() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		if err := p.write(job); err != nil {
+			p.recordErr(err)
+			continue
+		}
+		atomic.AddInt64(&p.entriesDone, 1)
+		p.reportProgress()
+	}
+}
+
+func (p *writerPool) write(job writeJob) error {
+	if err := os.MkdirAll(filepath.Dir(job.destPath), os.FileMode(0755)); err != nil {
+		os.Remove(job.tempFile)
+		return fmt.Errorf("failed to create parent directory for %s: %w", job.destPath, err)
+	}
+	if err := os.Rename(job.tempFile, job.destPath); err != nil {
+		os.Remove(job.tempFile)
+		return fmt.Errorf("failed to move asset into place at %s: %w", job.destPath, err)
+	}
+	return nil
+}
+
+// submit enqueues a staged blob for writing. It blocks if the pool's job
+// buffer is full, naturally throttling the tar-reading loop to the speed of
+// the slowest writer.
+func (p *writerPool) submit(destPath, tempFile string) {
+	p.jobs <- writeJob{destPath: destPath, tempFile: tempFile}
+}
+
+// addBytesRead records n additional bytes read from the archive and reports
+// progress.
+func (p *writerPool) addBytesRead(n int64) {
+	atomic.AddInt64(&p.bytesRead, n)
+	p.reportProgress()
+}
+
+func (p *writerPool) reportProgress() {
+	if p.onProgress == nil {
+		return
+	}
+	p.onProgress(Progress{
+		BytesRead:   atomic.LoadInt64(&p.bytesRead),
+		EntriesDone: int(atomic.LoadInt64(&p.entriesDone)),
+	})
+}
+
+func (p *writerPool) recordErr(err error) {
+	p.mu.Lock()
+	if p.firstErr == nil {
+		p.firstErr = err
+	}
+	p.mu.Unlock()
+}
+
+// closeAndWait signals that no more jobs will be submitted, waits for all
+// queued writes to finish, and returns the first error encountered by any
+// worker, if any.
+func (p *writerPool) closeAndWait() error {
+	close(p.jobs)
+	p.wg.Wait()
+	return p.firstErr
+}
@@ -0,0 +1,82 @@
+package unitypackage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestExtractToReportsProgress(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	var (
+		mu       sync.Mutex
+		calls    int
+		lastProg Progress
+	)
+	onProgress := func(p Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		lastProg = p
+	}
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	if err := u.ExtractTo(destDir, ExtractOptions{Workers: 2, OnProgress: onProgress}); err != nil {
+		t.Fatalf("ExtractTo failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls == 0 {
+		t.Fatalf("OnProgress was never called")
+	}
+	if lastProg.BytesRead == 0 {
+		t.Errorf("final Progress.BytesRead = 0, want > 0")
+	}
+	if lastProg.EntriesDone == 0 {
+		t.Errorf("final Progress.EntriesDone = 0, want > 0")
+	}
+}
+
+func TestExtractToSurfacesWorkerError(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+
+	// Pre-create a directory where Player.cs needs to land, so the writer
+	// pool's os.Rename of the staged asset onto that path fails.
+	blockedPath := filepath.Join(destDir, "Assets", "Scripts", "Player.cs")
+	if err := os.MkdirAll(blockedPath, 0755); err != nil {
+		t.Fatalf("failed to pre-create %s: %v", blockedPath, err)
+	}
+
+	if err := u.ExtractTo(destDir, ExtractOptions{}); err == nil {
+		t.Fatalf("ExtractTo succeeded, want an error from the blocked rename")
+	}
+}
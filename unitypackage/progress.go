@@ -0,0 +1,14 @@
+package unitypackage
+
+// Progress reports incremental extraction status. BytesRead accumulates as
+// the archive is decoded and is the only meaningful measure of a known
+// "total" before extraction finishes, since the uncompressed size of a tar
+// stream isn't known up front.
+type Progress struct {
+	BytesRead   int64
+	EntriesDone int
+}
+
+// ProgressFunc receives Progress updates from Unpacker.ExtractTo. It may be
+// called concurrently from multiple writer goroutines.
+type ProgressFunc func(Progress)
@@ -0,0 +1,243 @@
+// Package unitypackage provides read (Unpacker) and write (PackDirectory)
+// access to Unity's .unitypackage archive format: a gzip-compressed tar where each
+// asset is stored under a GUID-named directory containing an "asset" blob,
+// an "asset.meta" YAML sidecar, a "pathname" file with the project-relative
+// path, and an optional "preview.png".
+//
+// Known limitation: tar.TypeSymlink/tar.TypeLink entries are rejected
+// outright (see ErrUnsupportedEntryType) rather than created after
+// validating that their target resolves inside the destination directory.
+// The streaming single-pass extractor stages blobs and renames them into
+// place only once a GUID's pathname is known, so a link's target may not
+// exist yet when the link entry itself is decoded.
+package unitypackage
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrStreamConsumed is returned by Assets or ExtractTo when called on an
+// Unpacker whose archive stream has already been read to completion by a
+// prior call to either method. The archive is decoded in a single forward
+// pass over a (possibly decompressing) io.Reader with nothing buffered for
+// replay, so without this guard a second call would silently see only EOF
+// and "succeed" having read zero assets.
+var ErrStreamConsumed = errors.New("unitypackage: archive stream already consumed")
+
+// AssetEntry is a single asset parsed out of a .unitypackage archive.
+type AssetEntry struct {
+	GUID     string
+	Pathname string
+	Meta     []byte
+	Preview  []byte
+
+	// AssetReader provides the asset's contents. It is nil for entries that
+	// represent a directory (a pathname with no accompanying asset data).
+	// Otherwise it is backed by a temp file staged off the archive rather
+	// than an in-memory buffer, so large assets don't have to fit in RAM;
+	// callers must Close it once done to release that temp file.
+	AssetReader io.ReadCloser
+}
+
+// ExtractOptions customizes how Unpacker.ExtractTo lays assets out on disk.
+type ExtractOptions struct {
+	// Previews, when true, also writes each asset's preview.png (if present
+	// in the archive) as "<pathname>.preview.png".
+	Previews bool
+
+	// Workers is the number of concurrent writer goroutines used to move
+	// staged blobs into place. Zero selects runtime.NumCPU().
+	Workers int
+
+	// OnProgress, if set, is called as bytes are read from the archive and
+	// as entries are written to disk. It may be called concurrently from
+	// multiple goroutines.
+	OnProgress ProgressFunc
+}
+
+// Unpacker reads the contents of a .unitypackage archive.
+type Unpacker struct {
+	file   *os.File
+	source io.ReadCloser
+
+	// consumed is set as soon as Assets or ExtractTo starts reading source;
+	// it guards against a second call silently reading nothing from a
+	// stream that's already at EOF.
+	consumed bool
+
+	loaded bool
+	assets []AssetEntry
+
+	// assetStageDir holds temp files staged by Assets for AssetEntry.AssetReader.
+	// It's created lazily on first use and removed in Close.
+	assetStageDir string
+}
+
+// Open opens the .unitypackage file at path for reading.
+func Open(path string) (*Unpacker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	u, err := NewFromReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	u.file = file
+
+	return u, nil
+}
+
+// NewFromReader wraps an already-open .unitypackage stream for reading. The
+// stream may be a plain tar or wrapped in gzip, bzip2, xz, or zstd
+// compression; the format is auto-detected from its magic bytes.
+func NewFromReader(r io.Reader) (*Unpacker, error) {
+	source, err := DecompressStream(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create decompression reader: %w", err)
+	}
+
+	return &Unpacker{source: source}, nil
+}
+
+// Close releases resources held by the Unpacker, including any temp files
+// staged by Assets for an AssetReader the caller never got around to
+// closing itself.
+func (u *Unpacker) Close() error {
+	var err error
+	if u.source != nil {
+		err = u.source.Close()
+	}
+	if u.file != nil {
+		if fileErr := u.file.Close(); err == nil {
+			err = fileErr
+		}
+	}
+	if u.assetStageDir != "" {
+		os.RemoveAll(u.assetStageDir)
+	}
+	return err
+}
+
+// Assets parses the archive and returns its assets. The underlying stream is
+// read exactly once; subsequent calls return the cached result. Calling
+// Assets after ExtractTo (or vice versa) on the same Unpacker returns
+// ErrStreamConsumed, since both methods make a single forward pass over the
+// same archive stream.
+func (u *Unpacker) Assets() ([]AssetEntry, error) {
+	if u.loaded {
+		return u.assets, nil
+	}
+	if u.consumed {
+		return nil, ErrStreamConsumed
+	}
+	u.consumed = true
+
+	entriesByGUID := make(map[string]*AssetEntry)
+	var order []string
+
+	tarReader := tar.NewReader(u.source)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeSymlink || header.Typeflag == tar.TypeLink {
+			// See ErrUnsupportedEntryType: links are rejected outright rather
+			// than target-validated; see extract.go for why.
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedEntryType, header.Name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		guid, member := splitGUIDMember(header.Name)
+		if guid == "" {
+			continue
+		}
+
+		entry, ok := entriesByGUID[guid]
+		if !ok {
+			entry = &AssetEntry{GUID: guid}
+			entriesByGUID[guid] = entry
+			order = append(order, guid)
+		}
+
+		if member == "asset" {
+			stageDir, derr := u.stagingDir()
+			if derr != nil {
+				return nil, derr
+			}
+			stagedPath, _, serr := stageToTempFile(stageDir, tarReader)
+			if serr != nil {
+				return nil, fmt.Errorf("failed to stage %s: %w", header.Name, serr)
+			}
+			f, oerr := os.Open(stagedPath)
+			if oerr != nil {
+				return nil, fmt.Errorf("failed to open staged asset %s: %w", header.Name, oerr)
+			}
+			entry.AssetReader = f
+			continue
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		switch member {
+		case "pathname":
+			entry.Pathname = string(content)
+		case "asset.meta":
+			entry.Meta = content
+		case "preview.png":
+			entry.Preview = content
+		}
+	}
+
+	assets := make([]AssetEntry, 0, len(order))
+	for _, guid := range order {
+		assets = append(assets, *entriesByGUID[guid])
+	}
+
+	u.assets = assets
+	u.loaded = true
+
+	return assets, nil
+}
+
+// stagingDir returns the directory Assets stages asset blobs into, creating
+// it on first use.
+func (u *Unpacker) stagingDir() (string, error) {
+	if u.assetStageDir == "" {
+		dir, err := os.MkdirTemp("", "unitypackage-assets-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		u.assetStageDir = dir
+	}
+	return u.assetStageDir, nil
+}
+
+// splitGUIDMember splits a tar entry name of the form "<guid>/<member>" into
+// its two parts. It returns an empty guid for names that don't match this
+// shape.
+func splitGUIDMember(name string) (guid, member string) {
+	name = path.Clean(name)
+	idx := strings.IndexByte(name, '/')
+	if idx < 0 {
+		return "", ""
+	}
+	return name[:idx], name[idx+1:]
+}
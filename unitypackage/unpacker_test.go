@@ -0,0 +1,123 @@
+package unitypackage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractToAfterAssetsReturnsErrStreamConsumed(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	assets, err := u.Assets()
+	if err != nil {
+		t.Fatalf("Assets failed: %v", err)
+	}
+	if len(assets) == 0 {
+		t.Fatalf("Assets returned no entries")
+	}
+	for _, asset := range assets {
+		if asset.AssetReader != nil {
+			asset.AssetReader.Close()
+		}
+	}
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	if err := u.ExtractTo(destDir, ExtractOptions{}); !errors.Is(err, ErrStreamConsumed) {
+		t.Fatalf("ExtractTo after Assets error = %v, want ErrStreamConsumed", err)
+	}
+	if _, err := os.Stat(destDir); !os.IsNotExist(err) {
+		t.Errorf("ExtractTo after Assets should not have written to %s", destDir)
+	}
+}
+
+func TestExtractToTwiceReturnsErrStreamConsumed(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	if err := u.ExtractTo(destDir, ExtractOptions{}); err != nil {
+		t.Fatalf("first ExtractTo failed: %v", err)
+	}
+
+	if err := u.ExtractTo(filepath.Join(t.TempDir(), "dest2"), ExtractOptions{}); !errors.Is(err, ErrStreamConsumed) {
+		t.Fatalf("second ExtractTo error = %v, want ErrStreamConsumed", err)
+	}
+}
+
+func TestAssetsStagesAssetReaderToTempFileNotMemory(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "src")
+	buildProject(t, srcDir)
+
+	outFile := filepath.Join(t.TempDir(), "out.unitypackage")
+	if err := PackDirectory(srcDir, outFile); err != nil {
+		t.Fatalf("PackDirectory failed: %v", err)
+	}
+
+	u, err := Open(outFile)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer u.Close()
+
+	assets, err := u.Assets()
+	if err != nil {
+		t.Fatalf("Assets failed: %v", err)
+	}
+
+	var found bool
+	for _, asset := range assets {
+		if asset.AssetReader == nil {
+			continue
+		}
+		found = true
+
+		f, ok := asset.AssetReader.(*os.File)
+		if !ok {
+			t.Fatalf("AssetReader for %s is %T, want *os.File backed by a staged temp file", asset.GUID, asset.AssetReader)
+		}
+
+		got, rerr := io.ReadAll(f)
+		if rerr != nil {
+			t.Fatalf("failed to read AssetReader for %s: %v", asset.GUID, rerr)
+		}
+		if string(got) != "public class Player {}\n" {
+			t.Errorf("AssetReader content = %q, want %q", got, "public class Player {}\n")
+		}
+		if cerr := asset.AssetReader.Close(); cerr != nil {
+			t.Errorf("AssetReader.Close failed: %v", cerr)
+		}
+	}
+	if !found {
+		t.Fatalf("no asset in %v had a non-nil AssetReader", assets)
+	}
+
+	if err := u.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}